@@ -15,6 +15,9 @@
 package streams
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,10 +26,18 @@ import (
 	"github.com/aws/go-kafka-event-source/streams/sak"
 )
 
+// errUnacknowledgedBatchItem is recorded against a BatchItem whose
+// BatchExecutor returned without calling AckSuccess, AckFail, or AckSkip on
+// its handle. Unlike the pre-ack-handle AsyncBatcher, an unacknowledged item
+// is never implicitly treated as successful.
+var errUnacknowledgedBatchItem = errors.New("streams: batch item was not acknowledged by BatchExecutor")
+
 type BatchItem[K comparable, V any] struct {
-	batch unsafe.Pointer
-	Key   K
-	Value V
+	batch    unsafe.Pointer
+	Key      K
+	Value    V
+	attempts int
+	queuedAt time.Time
 }
 
 func batchFor[S any, K comparable, V any](bi BatchItem[K, V]) *Batch[S, K, V] {
@@ -36,8 +47,13 @@ func batchFor[S any, K comparable, V any](bi BatchItem[K, V]) *Batch[S, K, V] {
 type Batch[S any, K comparable, V any] struct {
 	EventContext *EventContext[S]
 	Items        []BatchItem[K, V]
-	callback     func(*Batch[S, K, V])
-	completed    int64
+	// Errors holds the terminal error for each item that was failed via
+	// AckFail and subsequently exhausted its RetryPolicy. Items that
+	// succeeded, were skipped, or are still retrying have no entry here.
+	Errors    map[K]error
+	callback  func(*Batch[S, K, V])
+	completed int64
+	errMux    sync.Mutex
 }
 
 func NewBatch[S any, K comparable, V any](ec *EventContext[S], cb func(*Batch[S, K, V])) *Batch[S, K, V] {
@@ -47,6 +63,11 @@ func NewBatch[S any, K comparable, V any](ec *EventContext[S], cb func(*Batch[S,
 	}
 }
 
+// completeItem marks one item of the batch as terminally resolved (success,
+// skip, or retries exhausted). It must never be called for an item that is
+// still being retried, so that EventContext.AsyncJobComplete (or callback)
+// only fires once every item has reached a terminal state, and so that
+// revocationWaiter accounting in partitionWorker stays correct through retries.
 func (b *Batch[S, K, V]) completeItem() {
 	if atomic.AddInt64(&b.completed, 1) == int64(len(b.Items)) {
 		if b.callback != nil {
@@ -59,6 +80,15 @@ func (b *Batch[S, K, V]) completeItem() {
 	}
 }
 
+func (b *Batch[S, K, V]) recordError(key K, err error) {
+	b.errMux.Lock()
+	if b.Errors == nil {
+		b.Errors = make(map[K]error)
+	}
+	b.Errors[key] = err
+	b.errMux.Unlock()
+}
+
 func (b *Batch[S, K, V]) Add(items ...BatchItem[K, V]) *Batch[S, K, V] {
 	for i := range items {
 		items[i].batch = unsafe.Pointer(b)
@@ -72,7 +102,95 @@ func (b *Batch[S, K, V]) AddKeyValue(key K, value V) *Batch[S, K, V] {
 	return b
 }
 
-type BatchExecutor[K comparable, V any] func(batch []BatchItem[K, V])
+// ackState tracks the disposition a BatchExecutor has reported for a
+// BatchItemHandle, via AckSuccess, AckFail, or AckSkip.
+type ackState int32
+
+const (
+	ackPending ackState = iota
+	ackSuccess
+	ackFail
+	ackSkip
+)
+
+// BatchItemHandle is handed to a BatchExecutor for every item in a batch. The
+// executor is expected to call exactly one of AckSuccess, AckFail, or AckSkip
+// for each handle before returning; an item left unacknowledged is treated as
+// failed, the same as an explicit AckFail.
+type BatchItemHandle[K comparable, V any] struct {
+	Key K
+	// Value is the value assigned to this item. Available for convenience, BatchItem
+	// in the closed-over Batch holds the authoritative copy.
+	Value V
+	// Attempts is the number of times this item (including the current one) has
+	// been handed to a BatchExecutor.
+	Attempts int
+	state    ackState
+	err      error
+}
+
+// AckSuccess marks this item as successfully processed.
+func (h *BatchItemHandle[K, V]) AckSuccess() {
+	atomic.StoreInt32((*int32)(&h.state), int32(ackSuccess))
+}
+
+// AckFail marks this item as failed with err. If the AsyncBatcher's
+// RetryPolicy permits another attempt, the item is re-queued and handed to a
+// future BatchExecutor call; otherwise err is recorded on the Batch returned
+// to the callback.
+func (h *BatchItemHandle[K, V]) AckFail(err error) {
+	h.err = err
+	atomic.StoreInt32((*int32)(&h.state), int32(ackFail))
+}
+
+// AckSkip marks this item as intentionally not processed, without treating it
+// as an error and without retrying it.
+func (h *BatchItemHandle[K, V]) AckSkip() {
+	atomic.StoreInt32((*int32)(&h.state), int32(ackSkip))
+}
+
+type BatchExecutor[K comparable, V any] func(batch []*BatchItemHandle[K, V])
+
+// RetryPolicy governs how an AsyncBatcher retries BatchItems that a
+// BatchExecutor acknowledges with AckFail. The zero value never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times an item may be handed to a
+	// BatchExecutor, including the first attempt. MaxAttempts <= 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry. Subsequent retries back
+	// off exponentially from this value, plus jitter.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff computed from BaseDelay. Defaults to 30x BaseDelay.
+	MaxDelay time.Duration
+	// Retryable, if set, is consulted on every AckFail to decide whether the
+	// error is worth retrying. A nil Retryable retries all errors.
+	Retryable func(err error) bool
+}
+
+func (p RetryPolicy) shouldRetry(attempts int, err error) bool {
+	if p.MaxAttempts <= 1 || attempts >= p.MaxAttempts {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return true
+}
+
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = p.BaseDelay * 30
+	}
+	delay := p.BaseDelay * time.Duration(int64(1)<<uint(sak.Min(attempts, 30)))
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
 
 type asyncBatchState int
 
@@ -109,6 +227,10 @@ type AsyncBatcher[S any, K comparable, V any] struct {
 	executingCount int
 	MaxBatchSize   int
 	BatchDelay     time.Duration
+	DLQPolicy      DLQPolicy
+	RetryPolicy    RetryPolicy
+	Metrics        Metrics
+	MetricsTags    map[string]string
 	mux            sync.Mutex
 }
 
@@ -130,6 +252,8 @@ func NewAsyncBatcher[S StateStore, K comparable, V any](eventSource *EventSource
 		batches:      batches,
 		MaxBatchSize: maxBatchSize,
 		BatchDelay:   sak.Abs(delay),
+		Metrics:      NoopMetrics{},
+		MetricsTags:  map[string]string{},
 	}
 }
 
@@ -140,6 +264,7 @@ func (ab *AsyncBatcher[S, K, V]) Add(batch *Batch[S, K, V]) {
 }
 
 func (ab *AsyncBatcher[S, K, V]) add(bi BatchItem[K, V]) {
+	bi.queuedAt = time.Now()
 	ab.mux.Lock()
 	if batch := ab.batchFor(bi); batch != nil {
 		ab.addToBatch(bi, batch)
@@ -165,8 +290,12 @@ func (ab *AsyncBatcher[S, K, V]) batchFor(item BatchItem[K, V]) *asyncBatch[K, V
 }
 
 func (ab *AsyncBatcher[S, K, V]) addToBatch(item BatchItem[K, V], batch *asyncBatch[K, V]) {
+	if !item.queuedAt.IsZero() {
+		ab.Metrics.Histogram(MetricBatchQueueTime, float64(time.Since(item.queuedAt)), ab.MetricsTags)
+	}
 	ab.assignments[item.Key] = batch
 	batch.add(item)
+	ab.Metrics.Gauge(MetricBatchFillRatio, float64(len(batch.items))/float64(ab.MaxBatchSize), ab.MetricsTags)
 
 	if len(batch.items) == ab.MaxBatchSize {
 		ab.conditionallyExecuteBatch(batch)
@@ -193,16 +322,75 @@ func (ab *AsyncBatcher[S, K, V]) conditionallyExecuteBatch(batch *asyncBatch[K,
 }
 
 func (ab *AsyncBatcher[S, K, V]) executeBatch(batch *asyncBatch[K, V]) {
-	ab.executor(batch.items)
-	for _, item := range batch.items {
-		batchFor[S](item).completeItem()
+	start := time.Now()
+	size := len(batch.items)
+	handles := make([]*BatchItemHandle[K, V], size)
+	for i, item := range batch.items {
+		handles[i] = &BatchItemHandle[K, V]{Key: item.Key, Value: item.Value, Attempts: item.attempts + 1}
 	}
+	ab.executor(handles)
+	ab.Metrics.Histogram(MetricBatchExecutionLatency, float64(time.Since(start)), ab.MetricsTags)
+	ab.Metrics.Histogram(MetricBatchSize, float64(size), ab.MetricsTags)
+
+	var retries []BatchItem[K, V]
+	for i, h := range handles {
+		item := batch.items[i]
+		switch ackState(atomic.LoadInt32((*int32)(&h.state))) {
+		case ackSuccess, ackSkip:
+			batchFor[S](item).completeItem()
+		default: // ackFail, or left ackPending
+			err := h.err
+			if err == nil {
+				err = errUnacknowledgedBatchItem
+			}
+			item.attempts = h.Attempts
+			if ab.RetryPolicy.shouldRetry(item.attempts, err) {
+				retries = append(retries, item)
+				continue
+			}
+			b := batchFor[S](item)
+			record, _ := b.EventContext.Input()
+			// The DeadLetter's Key/Value come from this item, not record: one
+			// EventContext/Batch fans out many BatchItems, so record's key/value
+			// belong to the original source event, not necessarily this item.
+			// record is still used for topic/partition/offset/headers provenance.
+			letter := newDeadLetter(record, err, item.attempts)
+			letter.Key = []byte(fmt.Sprint(item.Key))
+			letter.Value = []byte(fmt.Sprint(item.Value))
+			// Unlike partitionWorker.routeToDLQ, DLQStop does not leave this
+			// item pending indefinitely: a BatchItem's failure is scoped to
+			// itself, not the whole partition, so once retries are exhausted
+			// the terminal error is always surfaced to the EventContext via
+			// Batch.Errors/completeItem. routeDeadLetter's return value only
+			// matters for whether a DLQProduce attempt actually ran.
+			routeDeadLetter(ab.DLQPolicy, b.EventContext, newIncomingRecord(record), letter)
+			b.recordError(item.Key, err)
+			b.completeItem()
+		}
+	}
+
 	ab.mux.Lock()
 	ab.executingCount--
-	// TODO: handle errors right here as this may effect other batches
 	batch.reset(ab.assignments)
 	ab.flushPendingItems()
 	ab.mux.Unlock()
+
+	ab.requeueForRetry(retries)
+}
+
+// requeueForRetry re-admits items that AckFail'd but still have retries left,
+// after backing off per ab.RetryPolicy. Until a retried item is re-admitted
+// through ab.add, it is not part of any asyncBatch, so it does not hold a
+// batch slot open while it waits out its backoff.
+func (ab *AsyncBatcher[S, K, V]) requeueForRetry(items []BatchItem[K, V]) {
+	for _, item := range items {
+		item := item
+		if delay := ab.RetryPolicy.backoff(item.attempts); delay > 0 {
+			time.AfterFunc(delay, func() { ab.add(item) })
+		} else {
+			ab.add(item)
+		}
+	}
 }
 
 func (ab *AsyncBatcher[S, K, V]) flushPendingItems() {
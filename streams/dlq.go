@@ -0,0 +1,148 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// DLQPolicyMode governs how a partitionWorker or AsyncBatcher reacts when an
+// event, batch item, or undecodable record cannot be processed.
+type DLQPolicyMode int
+
+const (
+	// DLQStop halts the partitionWorker rather than making forward progress past
+	// the offending record. This mirrors the EventSource's default, pre-DLQ behavior.
+	DLQStop DLQPolicyMode = iota
+	// DLQDrop discards the offending record, commits its offset, and moves on.
+	// No record is produced anywhere.
+	DLQDrop
+	// DLQProduce diverts the offending record to a dead letter topic, selected by
+	// TopicSelector, before its offset is committed.
+	DLQProduce
+)
+
+// TopicSelector chooses the DLQ topic a failed record should be produced to.
+// It is invoked with the record that failed and the error that caused the failure.
+type TopicSelector func(record IncomingRecord, err error) string
+
+// DLQPolicy controls what an EventSource does with a record it cannot process:
+// an EventProcessor that returns an error, a forwardToEventSource failure, or a
+// BatchExecutor item that exhausts its RetryPolicy. Construct one with
+// StopDLQPolicy, DropDLQPolicy, or ProduceDLQPolicy.
+type DLQPolicy struct {
+	Mode          DLQPolicyMode
+	TopicSelector TopicSelector
+	Producer      DLQProducer
+}
+
+// StopDLQPolicy returns a DLQPolicy that halts processing on the first
+// unrecoverable error. This is the zero-value DLQPolicy.
+func StopDLQPolicy() DLQPolicy {
+	return DLQPolicy{Mode: DLQStop}
+}
+
+// DropDLQPolicy returns a DLQPolicy that silently commits past unrecoverable
+// errors without producing anywhere.
+func DropDLQPolicy() DLQPolicy {
+	return DLQPolicy{Mode: DLQDrop}
+}
+
+// ProduceDLQPolicy returns a DLQPolicy that diverts unrecoverable records to
+// producer, using selector to choose the destination topic per record.
+func ProduceDLQPolicy(producer DLQProducer, selector TopicSelector) DLQPolicy {
+	return DLQPolicy{Mode: DLQProduce, TopicSelector: selector, Producer: producer}
+}
+
+// DeadLetter captures everything needed to diagnose, and potentially replay, a
+// record that an EventSource could not process.
+type DeadLetter struct {
+	Topic           string
+	SourceTopic     string
+	SourcePartition int32
+	SourceOffset    int64
+	Key             []byte
+	Value           []byte
+	Headers         []kgo.RecordHeader
+	Err             error
+	Attempts        int
+	FirstFailure    time.Time
+}
+
+// DLQProducer is an optional, external sink for DeadLetters. It is NOT part
+// of the EOS transaction that commits the source offset: ProduceDeadLetter is
+// called outside that transaction, so a crash between it returning and the
+// offset commit can duplicate or drop the dead letter (at-least-once, not
+// exactly-once). Leave DLQPolicy.Producer nil to use the default instead,
+// which produces DeadLetters through the same eosProducerPool transaction
+// that commits the source offset. Implement this interface only when you
+// need a sink the EOS transaction can't reach (a different cluster, a file,
+// a queue).
+type DLQProducer interface {
+	ProduceDeadLetter(letter DeadLetter) error
+}
+
+func newDeadLetter(record *kgo.Record, err error, attempts int) DeadLetter {
+	return DeadLetter{
+		SourceTopic:     record.Topic,
+		SourcePartition: record.Partition,
+		SourceOffset:    record.Offset,
+		Key:             record.Key,
+		Value:           record.Value,
+		Headers:         record.Headers,
+		Err:             err,
+		Attempts:        attempts,
+		FirstFailure:    time.Now(),
+	}
+}
+
+// routeDeadLetter applies policy to letter, a DeadLetter already built for a
+// failure against incoming. It returns true when the caller should treat the
+// failure as terminally handled (offset may be committed, or an AsyncBatcher
+// item may be completed), and false when the caller should stop making
+// progress (DLQStop).
+//
+// Under DLQProduce, a nil policy.Producer routes through ec.producer: the
+// same transactional kgo.Client that will commit the source record's offset,
+// so the dead letter and the offset commit succeed or fail together. Setting
+// policy.Producer overrides this with an external DLQProducer instead, at the
+// cost of that at-least-once, non-transactional guarantee.
+func routeDeadLetter[T any](policy DLQPolicy, ec *EventContext[T], incoming IncomingRecord, letter DeadLetter) bool {
+	switch policy.Mode {
+	case DLQStop:
+		return false
+	case DLQDrop:
+		return true
+	case DLQProduce:
+		if policy.TopicSelector != nil {
+			letter.Topic = policy.TopicSelector(incoming, letter.Err)
+		}
+		var produceErr error
+		if policy.Producer != nil {
+			produceErr = policy.Producer.ProduceDeadLetter(letter)
+		} else if ec.producer != nil {
+			produceErr = ec.producer.produceDeadLetter(letter)
+		}
+		if produceErr != nil {
+			log.Errorf("failed to produce dead letter for %s[%d]@%d: %v", letter.SourceTopic, letter.SourcePartition, letter.SourceOffset, produceErr)
+			return false
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+type fakeDLQProducer struct {
+	letters []DeadLetter
+	err     error
+}
+
+func (f *fakeDLQProducer) ProduceDeadLetter(letter DeadLetter) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.letters = append(f.letters, letter)
+	return nil
+}
+
+func TestRouteDeadLetterStop(t *testing.T) {
+	record := &kgo.Record{Topic: "orders", Partition: 0, Offset: 1}
+	letter := newDeadLetter(record, errors.New("boom"), 1)
+	var ec EventContext[*IntStore]
+	if routeDeadLetter(StopDLQPolicy(), &ec, newIncomingRecord(record), letter) {
+		t.Fatal("expected DLQStop to report the record as unhandled")
+	}
+}
+
+func TestRouteDeadLetterDrop(t *testing.T) {
+	record := &kgo.Record{Topic: "orders", Partition: 0, Offset: 1}
+	letter := newDeadLetter(record, errors.New("boom"), 1)
+	var ec EventContext[*IntStore]
+	if !routeDeadLetter(DropDLQPolicy(), &ec, newIncomingRecord(record), letter) {
+		t.Fatal("expected DLQDrop to report the record as handled")
+	}
+}
+
+func TestRouteDeadLetterProduceExternal(t *testing.T) {
+	producer := &fakeDLQProducer{}
+	selector := func(record IncomingRecord, err error) string { return "orders.dlq" }
+	policy := ProduceDLQPolicy(producer, selector)
+	record := &kgo.Record{Topic: "orders", Partition: 0, Offset: 1}
+	letter := newDeadLetter(record, errors.New("boom"), 1)
+	var ec EventContext[*IntStore]
+
+	if !routeDeadLetter(policy, &ec, newIncomingRecord(record), letter) {
+		t.Fatal("expected DLQProduce to report the record as handled")
+	}
+	if len(producer.letters) != 1 {
+		t.Fatalf("expected exactly one dead letter, got %d", len(producer.letters))
+	}
+	if producer.letters[0].Topic != "orders.dlq" {
+		t.Fatalf("expected TopicSelector to set the topic, got %q", producer.letters[0].Topic)
+	}
+}
+
+func TestRouteDeadLetterProduceExternalFailure(t *testing.T) {
+	producer := &fakeDLQProducer{err: errors.New("unreachable")}
+	policy := ProduceDLQPolicy(producer, nil)
+	record := &kgo.Record{Topic: "orders", Partition: 0, Offset: 1}
+	letter := newDeadLetter(record, errors.New("boom"), 1)
+	var ec EventContext[*IntStore]
+
+	if routeDeadLetter(policy, &ec, newIncomingRecord(record), letter) {
+		t.Fatal("expected a failed external produce to report the record as unhandled")
+	}
+}
+
+func TestRouteDeadLetterUsesItemKeyValue(t *testing.T) {
+	producer := &fakeDLQProducer{}
+	policy := ProduceDLQPolicy(producer, nil)
+	record := &kgo.Record{Topic: "orders", Partition: 0, Offset: 1, Key: []byte("source-key"), Value: []byte("source-value")}
+	letter := newDeadLetter(record, errors.New("boom"), 1)
+	letter.Key = []byte("item-key")
+	letter.Value = []byte("item-value")
+	var ec EventContext[*IntStore]
+
+	if !routeDeadLetter(policy, &ec, newIncomingRecord(record), letter) {
+		t.Fatal("expected DLQProduce to report the record as handled")
+	}
+	if got := string(producer.letters[0].Key); got != "item-key" {
+		t.Fatalf("expected the DeadLetter's Key to be the overridden item key, got %q", got)
+	}
+	if got := string(producer.letters[0].Value); got != "item-value" {
+		t.Fatalf("expected the DeadLetter's Value to be the overridden item value, got %q", got)
+	}
+}
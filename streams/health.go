@@ -0,0 +1,65 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// partitionWorkerRegistries holds one *sync.Map (keyed by TopicPartition,
+// valued *partitionWorker[T]) per EventSource[T] instance, so HealthCheck can
+// enumerate a specific EventSource's partitionWorkers without depending on
+// where they happen to be tracked elsewhere. registerPartitionWorker and
+// unregisterPartitionWorker keep it in sync with partitionWorker lifecycle.
+var partitionWorkerRegistries sync.Map // map[any]*sync.Map, keyed by *EventSource[T]
+
+// registerPartitionWorker makes pw visible to es.HealthCheck. Called once a
+// partitionWorker is constructed.
+func registerPartitionWorker[T StateStore](es *EventSource[T], pw *partitionWorker[T]) {
+	registry, _ := partitionWorkerRegistries.LoadOrStore(es, &sync.Map{})
+	registry.(*sync.Map).Store(pw.topicPartition, pw)
+}
+
+// unregisterPartitionWorker removes tp from es.HealthCheck's view. Called once
+// a partitionWorker has been revoked.
+func unregisterPartitionWorker[T StateStore](es *EventSource[T], tp TopicPartition) {
+	if registry, ok := partitionWorkerRegistries.Load(es); ok {
+		registry.(*sync.Map).Delete(tp)
+	}
+}
+
+// HealthCheck aggregates the PartitionState of every partitionWorker this
+// EventSource owns. It returns nil when every partition is Active, and
+// otherwise an error enumerating the partitions that are Recovering,
+// Stalled, or Revoked. Intended to be wired directly into a k8s
+// liveness/readiness endpoint.
+func (es *EventSource[T]) HealthCheck() error {
+	var unhealthy []string
+	if registry, ok := partitionWorkerRegistries.Load(es); ok {
+		registry.(*sync.Map).Range(func(_, value any) bool {
+			pw := value.(*partitionWorker[T])
+			if state := pw.State(); state != Active {
+				unhealthy = append(unhealthy, fmt.Sprintf("%+v: %s", pw.topicPartition, state))
+			}
+			return true
+		})
+	}
+	if len(unhealthy) == 0 {
+		return nil
+	}
+	return fmt.Errorf("streams: unhealthy partitions: %s", strings.Join(unhealthy, ", "))
+}
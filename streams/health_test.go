@@ -0,0 +1,56 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHealthCheckAggregatesNonActivePartitions(t *testing.T) {
+	es := &EventSource[*IntStore]{}
+
+	active := &partitionWorker[*IntStore]{topicPartition: TopicPartition{Topic: "orders", Partition: 0}}
+	active.setState(Active)
+	registerPartitionWorker(es, active)
+
+	stalled := &partitionWorker[*IntStore]{topicPartition: TopicPartition{Topic: "orders", Partition: 1}}
+	stalled.setState(Stalled)
+	registerPartitionWorker(es, stalled)
+
+	err := es.HealthCheck()
+	if err == nil {
+		t.Fatal("expected HealthCheck to report the stalled partition")
+	}
+	if !strings.Contains(err.Error(), "Stalled") {
+		t.Fatalf("expected error to mention Stalled, got: %v", err)
+	}
+
+	unregisterPartitionWorker(es, stalled.topicPartition)
+	recovered := &partitionWorker[*IntStore]{topicPartition: stalled.topicPartition}
+	recovered.setState(Active)
+	registerPartitionWorker(es, recovered)
+
+	if err := es.HealthCheck(); err != nil {
+		t.Fatalf("expected HealthCheck to report healthy once all partitions are Active, got: %v", err)
+	}
+}
+
+func TestHealthCheckUnknownEventSourceIsHealthy(t *testing.T) {
+	es := &EventSource[*IntStore]{}
+	if err := es.HealthCheck(); err != nil {
+		t.Fatalf("expected an EventSource with no registered partitionWorkers to be healthy, got: %v", err)
+	}
+}
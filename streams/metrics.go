@@ -0,0 +1,186 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives the counters, gauges, and histograms that partitionWorker
+// and AsyncBatcher emit. tags identifies the topic/partition, batch, or other
+// dimension a sample belongs to. Implementations should be safe for
+// concurrent use; wrap one in NewAggregatedMetrics if the underlying sink
+// (Prometheus, statsd) is too expensive to call from a hot path.
+type Metrics interface {
+	Count(name string, delta int64, tags map[string]string)
+	Gauge(name string, value float64, tags map[string]string)
+	Histogram(name string, value float64, tags map[string]string)
+}
+
+// Metric names emitted by partitionWorker and AsyncBatcher.
+const (
+	MetricPartitionPending          = "streams.partition.pending"
+	MetricPartitionProcessed        = "streams.partition.processed"
+	MetricHandleEventLatency        = "streams.partition.handle_event.latency"
+	MetricHandleInterjectionLatency = "streams.partition.handle_interjection.latency"
+	MetricBatchFillRatio            = "streams.batcher.fill_ratio"
+	MetricBatchQueueTime            = "streams.batcher.pending_item.queue_time"
+	MetricBatchExecutionLatency     = "streams.batcher.execution.latency"
+	MetricBatchSize                 = "streams.batcher.batch_size"
+)
+
+// NoopMetrics discards every sample. It is the default Metrics when an
+// EventSource's source config does not specify one.
+type NoopMetrics struct{}
+
+func (NoopMetrics) Count(string, int64, map[string]string)       {}
+func (NoopMetrics) Gauge(string, float64, map[string]string)     {}
+func (NoopMetrics) Histogram(string, float64, map[string]string) {}
+
+func tagKey(name string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteByte('\x1f')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(tags[k])
+	}
+	return b.String()
+}
+
+type aggregatedSample struct {
+	name string
+	tags map[string]string
+}
+
+// AggregatedMetrics buffers Count/Gauge/Histogram deltas in memory and
+// flushes them to an underlying Metrics sink on a fixed interval, so hot
+// paths (handleEvent latency, pending/processed counts) never block on the
+// sink itself. Histograms are flushed as their mean over the interval.
+type AggregatedMetrics struct {
+	sink     Metrics
+	interval time.Duration
+	mux      sync.Mutex
+	samples  map[string]aggregatedSample
+	counts   map[string]int64
+	gauges   map[string]float64
+	histoSum map[string]float64
+	histoN   map[string]int64
+	stop     chan struct{}
+}
+
+// NewAggregatedMetrics constructs an AggregatedMetrics that flushes to sink
+// every flushInterval. Call Start to begin the flush loop and Stop to end it.
+func NewAggregatedMetrics(sink Metrics, flushInterval time.Duration) *AggregatedMetrics {
+	return &AggregatedMetrics{
+		sink:     sink,
+		interval: flushInterval,
+		samples:  make(map[string]aggregatedSample),
+		counts:   make(map[string]int64),
+		gauges:   make(map[string]float64),
+		histoSum: make(map[string]float64),
+		histoN:   make(map[string]int64),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start begins the background flush loop. It must only be called once.
+func (m *AggregatedMetrics) Start() {
+	go m.run()
+}
+
+// Stop ends the background flush loop after a final flush.
+func (m *AggregatedMetrics) Stop() {
+	close(m.stop)
+}
+
+func (m *AggregatedMetrics) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.flush()
+		case <-m.stop:
+			m.flush()
+			return
+		}
+	}
+}
+
+func (m *AggregatedMetrics) remember(name string, tags map[string]string) string {
+	key := tagKey(name, tags)
+	if _, ok := m.samples[key]; !ok {
+		m.samples[key] = aggregatedSample{name: name, tags: tags}
+	}
+	return key
+}
+
+func (m *AggregatedMetrics) Count(name string, delta int64, tags map[string]string) {
+	m.mux.Lock()
+	key := m.remember(name, tags)
+	m.counts[key] += delta
+	m.mux.Unlock()
+}
+
+func (m *AggregatedMetrics) Gauge(name string, value float64, tags map[string]string) {
+	m.mux.Lock()
+	key := m.remember(name, tags)
+	m.gauges[key] = value
+	m.mux.Unlock()
+}
+
+func (m *AggregatedMetrics) Histogram(name string, value float64, tags map[string]string) {
+	m.mux.Lock()
+	key := m.remember(name, tags)
+	m.histoSum[key] += value
+	m.histoN[key]++
+	m.mux.Unlock()
+}
+
+func (m *AggregatedMetrics) flush() {
+	m.mux.Lock()
+	samples, counts, gauges, histoSum, histoN := m.samples, m.counts, m.gauges, m.histoSum, m.histoN
+	m.samples = make(map[string]aggregatedSample)
+	m.counts = make(map[string]int64)
+	m.gauges = make(map[string]float64)
+	m.histoSum = make(map[string]float64)
+	m.histoN = make(map[string]int64)
+	m.mux.Unlock()
+
+	for key, sample := range samples {
+		if delta, ok := counts[key]; ok {
+			m.sink.Count(sample.name, delta, sample.tags)
+		}
+		if value, ok := gauges[key]; ok {
+			m.sink.Gauge(sample.name, value, sample.tags)
+		}
+		if n := histoN[key]; n > 0 {
+			m.sink.Histogram(sample.name, histoSum[key]/float64(n), sample.tags)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusMetrics is a Metrics implementation that registers a
+// CounterVec/GaugeVec/HistogramVec per metric name the first time it is
+// seen, keyed by that sample's tag names, and registers them with reg.
+type PrometheusMetrics struct {
+	reg prometheus.Registerer
+
+	mux        sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// NewPrometheusMetrics builds a PrometheusMetrics that registers its vectors
+// with reg, e.g. prometheus.DefaultRegisterer.
+func NewPrometheusMetrics(reg prometheus.Registerer) *PrometheusMetrics {
+	return &PrometheusMetrics{
+		reg:        reg,
+		counters:   make(map[string]*prometheus.CounterVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+	}
+}
+
+func tagNames(tags map[string]string) []string {
+	names := make([]string, 0, len(tags))
+	for k := range tags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// invalidPrometheusNameChar matches anything outside a Prometheus metric
+// name's allowed alphabet, [a-zA-Z_:][a-zA-Z0-9_:]*. The Metric* constants in
+// metrics.go use dotted namespacing (e.g. "streams.partition.pending") for
+// readability and compatibility with statsd-style sinks; sanitizeMetricName
+// rewrites the dots (or any other character outside [a-zA-Z0-9_:]) to
+// underscores before a name is ever handed to a CounterOpts/GaugeOpts/
+// HistogramOpts, since reg.MustRegister panics on an invalid descriptor. A
+// leading digit is not rewritten.
+var invalidPrometheusNameChar = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+func sanitizeMetricName(name string) string {
+	return invalidPrometheusNameChar.ReplaceAllString(name, "_")
+}
+
+func (p *PrometheusMetrics) Count(name string, delta int64, tags map[string]string) {
+	p.mux.Lock()
+	vec, ok := p.counters[name]
+	if !ok {
+		vec = prometheus.NewCounterVec(prometheus.CounterOpts{Name: sanitizeMetricName(name)}, tagNames(tags))
+		p.reg.MustRegister(vec)
+		p.counters[name] = vec
+	}
+	p.mux.Unlock()
+	vec.With(tags).Add(float64(delta))
+}
+
+func (p *PrometheusMetrics) Gauge(name string, value float64, tags map[string]string) {
+	p.mux.Lock()
+	vec, ok := p.gauges[name]
+	if !ok {
+		vec = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: sanitizeMetricName(name)}, tagNames(tags))
+		p.reg.MustRegister(vec)
+		p.gauges[name] = vec
+	}
+	p.mux.Unlock()
+	vec.With(tags).Set(value)
+}
+
+func (p *PrometheusMetrics) Histogram(name string, value float64, tags map[string]string) {
+	p.mux.Lock()
+	vec, ok := p.histograms[name]
+	if !ok {
+		vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: sanitizeMetricName(name)}, tagNames(tags))
+		p.reg.MustRegister(vec)
+		p.histograms[name] = vec
+	}
+	p.mux.Unlock()
+	vec.With(tags).Observe(value)
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestPrometheusMetricsRegistersDottedNames reproduces the panic client_golang
+// raises when a CounterVec/GaugeVec/HistogramVec is registered with a dotted
+// name like MetricPartitionPending ("streams.partition.pending"): Prometheus
+// metric names may only contain [a-zA-Z0-9_:]. It registers real vectors
+// against a real prometheus.Registry, rather than only exercising tagKey.
+func TestPrometheusMetricsRegistersDottedNames(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	pm := NewPrometheusMetrics(reg)
+	tags := map[string]string{"topic": "orders", "partition": "0"}
+
+	pm.Count(MetricPartitionPending, 1, tags)
+	pm.Gauge(MetricPartitionProcessed, 3, tags)
+	pm.Histogram(MetricHandleEventLatency, 1.5, tags)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	if len(families) != 3 {
+		t.Fatalf("expected 3 registered metric families, got %d", len(families))
+	}
+	for _, mf := range families {
+		if strings.ContainsRune(mf.GetName(), '.') {
+			t.Fatalf("registered metric name %q still contains a dot", mf.GetName())
+		}
+	}
+}
+
+func TestSanitizeMetricName(t *testing.T) {
+	if got := sanitizeMetricName("streams.partition.pending"); got != "streams_partition_pending" {
+		t.Fatalf("expected dots to become underscores, got %q", got)
+	}
+	if got := sanitizeMetricName("already_valid:name"); got != "already_valid:name" {
+		t.Fatalf("expected an already-valid name to pass through unchanged, got %q", got)
+	}
+}
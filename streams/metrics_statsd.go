@@ -0,0 +1,81 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// StatsdMetrics is a Metrics implementation that writes Datadog-flavored
+// statsd lines (name:value|type|#tag:value,...) over a UDP connection.
+type StatsdMetrics struct {
+	conn   net.Conn
+	prefix string
+}
+
+// NewStatsdMetrics dials addr (host:port) over UDP and returns a
+// StatsdMetrics that prefixes every metric name with prefix followed by a dot,
+// if prefix is non-empty.
+func NewStatsdMetrics(addr, prefix string) (*StatsdMetrics, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("streams: failed to dial statsd at %s: %w", addr, err)
+	}
+	if prefix != "" {
+		prefix += "."
+	}
+	return &StatsdMetrics{conn: conn, prefix: prefix}, nil
+}
+
+func (s *StatsdMetrics) send(name, valueAndType string, tags map[string]string) {
+	var b strings.Builder
+	b.WriteString(s.prefix)
+	b.WriteString(name)
+	b.WriteByte(':')
+	b.WriteString(valueAndType)
+	if len(tags) > 0 {
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("|#")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(k)
+			b.WriteByte(':')
+			b.WriteString(tags[k])
+		}
+	}
+	// best effort: a dropped metrics datagram should never affect stream processing
+	s.conn.Write([]byte(b.String()))
+}
+
+func (s *StatsdMetrics) Count(name string, delta int64, tags map[string]string) {
+	s.send(name, fmt.Sprintf("%d|c", delta), tags)
+}
+
+func (s *StatsdMetrics) Gauge(name string, value float64, tags map[string]string) {
+	s.send(name, fmt.Sprintf("%g|g", value), tags)
+}
+
+func (s *StatsdMetrics) Histogram(name string, value float64, tags map[string]string) {
+	s.send(name, fmt.Sprintf("%g|h", value), tags)
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import "testing"
+
+func TestTagKeyIsOrderIndependent(t *testing.T) {
+	a := tagKey("streams.partition.pending", map[string]string{"topic": "orders", "partition": "0"})
+	b := tagKey("streams.partition.pending", map[string]string{"partition": "0", "topic": "orders"})
+	if a != b {
+		t.Fatalf("expected tagKey to be independent of map iteration order, got %q vs %q", a, b)
+	}
+}
+
+func TestTagKeyDistinguishesValues(t *testing.T) {
+	a := tagKey("streams.partition.pending", map[string]string{"partition": "0"})
+	b := tagKey("streams.partition.pending", map[string]string{"partition": "1"})
+	if a == b {
+		t.Fatal("expected tagKey to distinguish different tag values")
+	}
+}
+
+func TestTagKeyNoTags(t *testing.T) {
+	if got := tagKey("streams.partition.pending", nil); got != "streams.partition.pending" {
+		t.Fatalf("expected a nil tag set to fall back to the bare name, got %q", got)
+	}
+}
@@ -41,12 +41,70 @@ const (
 	unknownType ExecutionState = 3
 )
 
+// ConsumptionMode describes whether a partitionWorker is processing records
+// live, within its configured timestamp bounds, or backfilling: catching up
+// through a best-effort mode used for records older than BackfillWindow.
+type ConsumptionMode int
+
+const (
+	// LiveMode is the default, strict processing mode.
+	LiveMode ConsumptionMode = iota
+	// BackfillMode trades strict changelog reads and per-record transactions
+	// for throughput while a partitionWorker catches up past old history.
+	BackfillMode
+)
+
+// ModeChangeCallback is invoked whenever a partitionWorker transitions between
+// LiveMode and BackfillMode, so callers can observe backfill/live transitions.
+type ModeChangeCallback func(TopicPartition, ConsumptionMode)
+
+// PartitionState describes the lifecycle stage of a partitionWorker, as
+// reported to any attached PartitionObserver.
+type PartitionState int
+
+const (
+	// Recovering is the initial state: the partitionWorker is populating its
+	// changelog, if any, and has not yet started consuming live records.
+	Recovering PartitionState = iota
+	// Active means the partitionWorker is consuming and making progress.
+	Active
+	// Stalled means the partitionWorker has not advanced its highestOffset
+	// within its configured stall window, despite not being revoked.
+	Stalled
+	// Revoked means the partitionWorker has been, or is being, torn down.
+	Revoked
+)
+
+func (s PartitionState) String() string {
+	switch s {
+	case Recovering:
+		return "Recovering"
+	case Active:
+		return "Active"
+	case Stalled:
+		return "Stalled"
+	case Revoked:
+		return "Revoked"
+	default:
+		return "Unknown"
+	}
+}
+
+// PartitionObserver is notified every time a partitionWorker transitions
+// between PartitionStates. Attach one via the source config's
+// PartitionObservers so it can be wired into health checks, dashboards, or
+// k8s liveness/readiness endpoints.
+type PartitionObserver interface {
+	OnStateChange(tp TopicPartition, state PartitionState)
+}
+
 type partitionWorker[T StateStore] struct {
 	eosProducer         *eosProducerPool[T]
 	partitionInput      chan []*kgo.Record
 	eventInput          chan *EventContext[T]
 	asyncCompleter      asyncCompleter[T]
 	interjectionChannel chan *interjection[T]
+	skipInput           chan *EventContext[T]
 	stopSignal          chan struct{}
 	revokedSignal       chan struct{}
 	stopped             chan struct{}
@@ -58,6 +116,19 @@ type partitionWorker[T StateStore] struct {
 	highestOffset       int64
 	topicPartition      TopicPartition
 	revocationWaiter    sync.WaitGroup
+	dlqPolicy           DLQPolicy
+	minTimestamp        time.Time
+	maxTimestamp        time.Time
+	backfillWindow      time.Duration
+	mode                ConsumptionMode
+	onModeChange        ModeChangeCallback
+	state               int32
+	observers           []PartitionObserver
+	stallWindow         time.Duration
+	stalledAtOffset     int64
+	metrics             Metrics
+	metricsTags         map[string]string
+	metricsInterval     time.Duration
 }
 
 func newPartitionWorker[T StateStore](
@@ -66,7 +137,7 @@ func newPartitionWorker[T StateStore](
 	commitLog *eosCommitLog,
 	changeLog changeLogPartition[T],
 	eosProducer *eosProducerPool[T],
-	waiter func()) *partitionWorker[T] {
+	waiter func() error) *partitionWorker[T] {
 
 	eosConfig := eventSource.source.config.EosConfig
 
@@ -86,16 +157,68 @@ func newPartitionWorker[T StateStore](
 		},
 		partitionInput:      make(chan []*kgo.Record, 128),
 		eventInput:          make(chan *EventContext[T], recordsInputSize),
+		skipInput:           make(chan *EventContext[T], recordsInputSize),
 		interjectionChannel: make(chan *interjection[T], 1),
 		runStatus:           eventSource.runStatus.Fork(),
 		highestOffset:       -1,
+		dlqPolicy:           eventSource.source.config.DLQPolicy,
+		minTimestamp:        eventSource.source.config.MinTimestamp,
+		maxTimestamp:        eventSource.source.config.MaxTimestamp,
+		backfillWindow:      eventSource.source.config.BackfillWindow,
+		onModeChange:        eventSource.source.config.OnModeChange,
+		observers:           eventSource.source.config.PartitionObservers,
+		stallWindow:         eventSource.source.config.StallWindow,
+		stalledAtOffset:     -1,
+		metrics:             eventSource.source.config.Metrics,
+		metricsInterval:     eventSource.source.config.MetricsFlushInterval,
+		metricsTags: map[string]string{
+			"topic":     topicPartition.Topic,
+			"partition": fmt.Sprintf("%d", topicPartition.Partition),
+		},
+	}
+	if pw.metrics == nil {
+		pw.metrics = NoopMetrics{}
 	}
+	if pw.metricsInterval <= 0 {
+		pw.metricsInterval = 10 * time.Second
+	}
+	eosProducer.setMetrics(pw.metrics, pw.metricsTags)
+	if opt, err := eventSource.source.config.SASLConfig.kgoOpt(); err != nil {
+		log.Errorf("invalid SASLConfig for %+v: %v", topicPartition, err)
+	} else if opt != nil {
+		eosProducer.setSASL(opt)
+	}
+	registerPartitionWorker(eventSource, pw)
 
 	go pw.work(pw.eventSource.interjections, waiter, commitLog)
 
 	return pw
 }
 
+// notifyState fires OnStateChange on every attached PartitionObserver,
+// unconditionally. Use setState instead when the call site should only
+// notify on an actual transition.
+func (pw *partitionWorker[T]) notifyState(state PartitionState) {
+	for _, o := range pw.observers {
+		o.OnStateChange(pw.topicPartition, state)
+	}
+}
+
+// setState transitions the partitionWorker to state, notifying observers only
+// when it actually changes. Safe to call from any goroutine.
+func (pw *partitionWorker[T]) setState(state PartitionState) {
+	if PartitionState(atomic.SwapInt32(&pw.state, int32(state))) == state {
+		return
+	}
+	pw.notifyState(state)
+}
+
+// State returns the partitionWorker's current PartitionState. Safe to call
+// from any goroutine, used by EventSource.HealthCheck.
+func (pw *partitionWorker[T]) State() PartitionState {
+	return PartitionState(atomic.LoadInt32(&pw.state))
+}
+
 func (pw *partitionWorker[T]) add(records []*kgo.Record) {
 	if pw.isRevoked() {
 		return
@@ -105,6 +228,7 @@ func (pw *partitionWorker[T]) add(records []*kgo.Record) {
 }
 
 func (pw *partitionWorker[T]) revoke() {
+	pw.setState(Revoked)
 	pw.runStatus.Halt()
 }
 
@@ -129,6 +253,7 @@ func (pw *partitionWorker[T]) pushRecords() {
 			<-pw.stopped
 			close(pw.partitionInput)
 			close(pw.eventInput)
+			close(pw.skipInput)
 			close(pw.asyncCompleter.asyncJobs)
 			log.Debugf("Closed worker for %+v", pw.topicPartition)
 			return
@@ -139,23 +264,102 @@ func (pw *partitionWorker[T]) pushRecords() {
 func (pw *partitionWorker[T]) scheduleTxnAndExecution(records []*kgo.Record) {
 	pw.revocationWaiter.Add(len(records)) // optimistically do one add call
 	for _, record := range records {
-		if record != nil && record.Offset >= pw.highestOffset {
-			ec := newEventContext(pw.runStatus.Ctx(), record, pw.changeLog.changeLogData(), pw)
-			pw.eosProducer.addEventContext(ec)
-			pw.eventInput <- ec
-		} else {
+		if record == nil || record.Offset < pw.highestOffset {
 			pw.revocationWaiter.Done() // in the rare occasion this is a stale evetn, decrement the revocation waiter
+			continue
 		}
+		if pw.outOfTimestampBounds(record) {
+			pw.scheduleSkip(record)
+			continue
+		}
+		pw.updateConsumptionMode(record)
+		ec := newEventContext(pw.runStatus.Ctx(), record, pw.changeLog.changeLogData(), pw)
+		pw.eosProducer.addEventContext(ec)
+		pw.eventInput <- ec
+	}
+}
+
+// outOfTimestampBounds reports whether record falls outside the partitionWorker's
+// configured MinTimestamp/MaxTimestamp window.
+func (pw *partitionWorker[T]) outOfTimestampBounds(record *kgo.Record) bool {
+	if !pw.minTimestamp.IsZero() && record.Timestamp.Before(pw.minTimestamp) {
+		return true
+	}
+	if !pw.maxTimestamp.IsZero() && record.Timestamp.After(pw.maxTimestamp) {
+		return true
 	}
+	return false
 }
 
-func (pw *partitionWorker[T]) work(interjections []interjection[T], waiter func(), commitLog *eosCommitLog) {
+// scheduleSkip hands a record outside the timestamp window to work(), rather
+// than blocking on ec.producerChan here: scheduleSkip runs in the
+// pushRecords() goroutine, which must stay non-blocking so in-bounds records
+// keep flowing even while a backfill is skipping most of the partition.
+func (pw *partitionWorker[T]) scheduleSkip(record *kgo.Record) {
+	ec := newEventContext(pw.runStatus.Ctx(), record, pw.changeLog.changeLogData(), pw)
+	pw.eosProducer.addEventContext(ec)
+	pw.skipInput <- ec
+}
+
+// handleSkippedEvent completes an EventContext scheduled by scheduleSkip for a
+// record outside the timestamp window: its offset still participates in, and
+// is advanced by, the same transaction that commits in-bounds records. Unlike
+// scheduleSkip, this runs on the work() goroutine, so blocking on
+// ec.producerChan here is safe.
+func (pw *partitionWorker[T]) handleSkippedEvent(ec *EventContext[T]) {
+	offset := ec.Offset()
+	atomic.AddInt64(&pw.pending, -1)
+	ec.producer = <-ec.producerChan
+	if ec.producer != nil {
+		ec.complete()
+	}
+	pw.highestOffset = offset + 1
+	atomic.AddInt64(&pw.processed, 1)
+}
+
+// updateConsumptionMode switches the partitionWorker between LiveMode and
+// BackfillMode based on how far record.Timestamp trails the configured
+// BackfillWindow, invoking onModeChange on every transition. In BackfillMode,
+// the eosProducerPool batches larger transactions and skips changelog reads
+// that aren't strictly required, until the worker catches up to within the
+// window and switches back to the strict, live path.
+func (pw *partitionWorker[T]) updateConsumptionMode(record *kgo.Record) {
+	if pw.backfillWindow <= 0 {
+		return
+	}
+	mode := LiveMode
+	if record.Timestamp.Before(time.Now().Add(-pw.backfillWindow)) {
+		mode = BackfillMode
+	}
+	if mode == pw.mode {
+		return
+	}
+	pw.mode = mode
+	pw.eosProducer.setBackfillMode(mode == BackfillMode)
+	if pw.onModeChange != nil {
+		pw.onModeChange(pw.topicPartition, mode)
+	}
+}
+
+// changelogReconnectPolicy governs the backoff between retries of the
+// changelog-population waiter when the underlying kgo.Client reports a fatal
+// error while draining the changelog, rather than deadlocking the worker.
+var changelogReconnectPolicy = RetryPolicy{
+	MaxAttempts: 0, // retried indefinitely; bounded only by pw.runStatus
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+func (pw *partitionWorker[T]) work(interjections []interjection[T], waiter func() error, commitLog *eosCommitLog) {
 	elapsed := sincer{time.Now()}
 	// don't start consuming until this function returns
 	// this function will block until all changelogs for this partition are populated
 	pw.highestOffset = commitLog.lastProcessed(pw.topicPartition)
+	pw.notifyState(Recovering)
 	log.Debugf("partitionWorker initialized %+v with lastProcessed offset: %d in %v", pw.topicPartition, pw.highestOffset, elapsed)
-	waiter()
+	if !pw.populateChangelog(waiter) {
+		return // revoked while draining the changelog
+	}
 	go pw.pushRecords()
 	log.Debugf("partitionWorker activated %+v in %v, interjectionCount: %d", pw.topicPartition, elapsed, len(interjections))
 	ijPtrs := sak.ToPtrSlice(interjections)
@@ -164,6 +368,19 @@ func (pw *partitionWorker[T]) work(interjections []interjection[T], waiter func(
 		ij.tick()
 	}
 	pw.eventSource.source.onPartitionActivated(pw.topicPartition.Partition)
+	pw.setState(Active)
+
+	var stallTicker *time.Ticker
+	var stallTick <-chan time.Time
+	if pw.stallWindow > 0 {
+		stallTicker = time.NewTicker(pw.stallWindow)
+		stallTick = stallTicker.C
+		defer stallTicker.Stop()
+	}
+
+	metricsTicker := time.NewTicker(pw.metricsInterval)
+	defer metricsTicker.Stop()
+
 	for {
 		select {
 		case ec := <-pw.eventInput:
@@ -171,6 +388,8 @@ func (pw *partitionWorker[T]) work(interjections []interjection[T], waiter func(
 			// ec := newEventContext(pw.runStatus.Ctx(), record, pw.changeLog.changeLogData(), pw)
 			pw.handleEvent(ec)
 			// }
+		case ec := <-pw.skipInput:
+			pw.handleSkippedEvent(ec)
 		case job := <-pw.asyncCompleter.asyncJobs:
 			// TODO: if the partition was reject and we have not tried to produce yet
 			// drop this event. This is tricky because we need to know if we are buffered or not
@@ -182,20 +401,79 @@ func (pw *partitionWorker[T]) work(interjections []interjection[T], waiter func(
 			default:
 			}
 		case ij := <-pw.interjectionChannel:
+			start := time.Now()
 			pw.handleInterjection(ij)
+			pw.metrics.Histogram(MetricHandleInterjectionLatency, float64(time.Since(start)), pw.metricsTags)
 			ij.tick()
+		case <-stallTick:
+			pw.checkForStall()
+		case <-metricsTicker.C:
+			pw.reportGauges()
 		case <-pw.stopSignal:
 			for _, ij := range ijPtrs {
 				ij.cancel()
 			}
 			go pw.waitForRevocation()
 		case <-pw.revokedSignal:
+			pw.setState(Revoked)
+			unregisterPartitionWorker(pw.eventSource, pw.topicPartition)
 			pw.stopped <- struct{}{}
 			return
 		}
 	}
 }
 
+// populateChangelog runs waiter to completion, retrying with
+// changelogReconnectPolicy's exponential, jittered backoff whenever waiter
+// reports a fatal kgo.Client error rather than letting the worker deadlock.
+// Returns false if the partitionWorker was revoked before waiter succeeded.
+func (pw *partitionWorker[T]) populateChangelog(waiter func() error) bool {
+	for attempt := 1; ; attempt++ {
+		if pw.isRevoked() {
+			return false
+		}
+		err := waiter()
+		if err == nil {
+			return true
+		}
+		log.Errorf("changelog population for %+v failed on attempt %d: %v", pw.topicPartition, attempt, err)
+		delay := changelogReconnectPolicy.backoff(attempt)
+		if delay <= 0 {
+			delay = changelogReconnectPolicy.BaseDelay
+		}
+		select {
+		case <-time.After(delay):
+		case <-pw.runStatus.Done():
+			return false
+		}
+	}
+}
+
+// checkForStall transitions the partitionWorker to Stalled if highestOffset
+// hasn't advanced since the last stall window tick while records are still
+// pending, or back to Active once either condition is no longer true.
+//
+// An unchanged highestOffset on its own does not mean the partitionWorker is
+// stuck: a low-traffic topic can legitimately have nothing new to consume for
+// longer than stallWindow. Requiring pending > 0 as well restricts Stalled to
+// the case HealthCheck() actually cares about for k8s liveness/readiness:
+// records have been handed to this worker and it has failed to make progress
+// on them, not that the source topic itself is quiet.
+//
+// A partitionWorker that is already Revoked is left Revoked.
+func (pw *partitionWorker[T]) checkForStall() {
+	if pw.State() == Revoked {
+		return
+	}
+	offset := pw.highestOffset
+	if offset == pw.stalledAtOffset && atomic.LoadInt64(&pw.pending) > 0 {
+		pw.setState(Stalled)
+		return
+	}
+	pw.stalledAtOffset = offset
+	pw.setState(Active)
+}
+
 func (pw *partitionWorker[T]) waitForRevocation() {
 	pw.revocationWaiter.Wait() // wait until all pending events have been accpted by a producerNode
 	pw.revokedSignal <- struct{}{}
@@ -247,26 +525,53 @@ func (pw *partitionWorker[T]) handleInterjection(inter *interjection[T]) {
 }
 
 func (pw *partitionWorker[T]) handleEvent(ec *EventContext[T]) bool {
+	start := time.Now()
 	offset := ec.Offset()
 	atomic.AddInt64(&pw.pending, -1)
 	pw.forwardToEventSource(ec)
 	pw.highestOffset = offset + 1
 	atomic.AddInt64(&pw.processed, 1)
+	pw.metrics.Histogram(MetricHandleEventLatency, float64(time.Since(start)), pw.metricsTags)
 	return true
 }
 
+// reportGauges snapshots pw.pending/pw.processed into Metrics. Emitting these
+// from a low-frequency ticker, rather than on every atomic.AddInt64 call in
+// handleEvent/add, keeps the hot path free of Metrics sink contention.
+func (pw *partitionWorker[T]) reportGauges() {
+	pw.metrics.Gauge(MetricPartitionPending, float64(atomic.LoadInt64(&pw.pending)), pw.metricsTags)
+	pw.metrics.Gauge(MetricPartitionProcessed, float64(atomic.LoadInt64(&pw.processed)), pw.metricsTags)
+}
+
 func (pw *partitionWorker[T]) forwardToEventSource(ec *EventContext[T]) {
 	ec.producer = <-ec.producerChan
 	if ec.producer == nil {
 		// if we're revoked, don't even add this to the onDeck producer
 		return
 	}
-	record, _ := ec.Input()
+	record, decodeErr := ec.Input()
+	if decodeErr != nil {
+		pw.routeToDLQ(record, decodeErr, 1, ec)
+		return
+	}
 	state, err := pw.eventSource.handleEvent(ec, record)
 
 	if err != nil {
 		log.Errorf("%v", err)
+		pw.routeToDLQ(record, err, 1, ec)
 	} else if state == Complete {
 		ec.complete()
 	}
 }
+
+// routeToDLQ applies pw.dlqPolicy to a record that handleEvent (or decoding)
+// could not process. When the policy allows forward progress (DLQDrop,
+// DLQProduce), ec is completed and its offset is committed as usual; under
+// DLQStop the EventContext is left incomplete and the partitionWorker stalls
+// on this record, matching pre-DLQ behavior.
+func (pw *partitionWorker[T]) routeToDLQ(record *kgo.Record, err error, attempts int, ec *EventContext[T]) {
+	letter := newDeadLetter(record, err, attempts)
+	if routeDeadLetter(pw.dlqPolicy, ec, newIncomingRecord(record), letter) {
+		ec.complete()
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import "testing"
+
+func TestCheckForStallIdlePartitionStaysActive(t *testing.T) {
+	pw := &partitionWorker[*IntStore]{highestOffset: 5, stalledAtOffset: 5}
+	pw.setState(Active)
+
+	pw.checkForStall()
+
+	if got := pw.State(); got != Active {
+		t.Fatalf("expected an idle partition with nothing pending to stay Active, got %v", got)
+	}
+}
+
+func TestCheckForStallStuckPartitionWithPendingRecords(t *testing.T) {
+	pw := &partitionWorker[*IntStore]{highestOffset: 5, stalledAtOffset: 5, pending: 1}
+	pw.setState(Active)
+
+	pw.checkForStall()
+
+	if got := pw.State(); got != Stalled {
+		t.Fatalf("expected a partition with pending records stuck at the same offset to be Stalled, got %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	var zero RetryPolicy
+	if zero.shouldRetry(1, errors.New("boom")) {
+		t.Fatal("expected the zero-value RetryPolicy to never retry")
+	}
+
+	p := RetryPolicy{MaxAttempts: 3}
+	if !p.shouldRetry(1, errors.New("boom")) {
+		t.Fatal("expected a retry below MaxAttempts")
+	}
+	if p.shouldRetry(3, errors.New("boom")) {
+		t.Fatal("expected no retry once attempts reaches MaxAttempts")
+	}
+
+	retryable := RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return err.Error() == "retryable" },
+	}
+	if !retryable.shouldRetry(1, errors.New("retryable")) {
+		t.Fatal("expected Retryable to permit this error")
+	}
+	if retryable.shouldRetry(1, errors.New("fatal")) {
+		t.Fatal("expected Retryable to reject this error")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	var zero RetryPolicy
+	if zero.backoff(1) != 0 {
+		t.Fatal("expected a zero BaseDelay to produce no backoff")
+	}
+
+	p := RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := p.backoff(attempt)
+		if delay <= 0 || delay > p.MaxDelay {
+			t.Fatalf("attempt %d: backoff %v outside (0, %v]", attempt, delay, p.MaxDelay)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/stscreds"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	awssasl "github.com/twmb/franz-go/pkg/sasl/aws"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// SASLMechanism identifies which SASL mechanism a SASLConfig should build.
+type SASLMechanism int
+
+const (
+	// SASLNone disables SASL entirely. The zero value of SASLConfig.
+	SASLNone SASLMechanism = iota
+	// SASLPlain uses SASL/PLAIN with User/Pass.
+	SASLPlain
+	// SASLScramSha256 uses SASL/SCRAM-SHA-256 with User/Pass.
+	SASLScramSha256
+	// SASLScramSha512 uses SASL/SCRAM-SHA-512 with User/Pass.
+	SASLScramSha512
+	// SASLAwsMskIam uses SASL/OAUTHBEARER-AWS (AWS_MSK_IAM) against Amazon MSK,
+	// authenticating with AWSConfig's credential provider.
+	SASLAwsMskIam
+)
+
+// SASLConfig plugs a SASL mechanism into an EventSource's source config. It is
+// currently applied to every producer in the EOS eosProducerPool (see
+// kgoOpt's doc comment for the consumer-side gap). Build one with
+// NewPlainSASLConfig, NewScramSASLConfig, or NewAWSMSKIAMSASLConfig rather
+// than constructing it directly.
+type SASLConfig struct {
+	Mechanism SASLMechanism
+	User      string
+	Pass      string
+	AWSConfig awssdk.Config
+}
+
+// NewPlainSASLConfig builds a SASLConfig for SASL/PLAIN.
+func NewPlainSASLConfig(user, pass string) SASLConfig {
+	return SASLConfig{Mechanism: SASLPlain, User: user, Pass: pass}
+}
+
+// NewScramSASLConfig builds a SASLConfig for SASL/SCRAM-SHA-256 or
+// SASL/SCRAM-SHA-512, selected by sha512.
+func NewScramSASLConfig(user, pass string, sha512 bool) SASLConfig {
+	if sha512 {
+		return SASLConfig{Mechanism: SASLScramSha512, User: user, Pass: pass}
+	}
+	return SASLConfig{Mechanism: SASLScramSha256, User: user, Pass: pass}
+}
+
+// NewAWSMSKIAMSASLConfig builds a SASLConfig for Amazon MSK IAM
+// authentication (SASL/OAUTHBEARER-AWS), deriving credentials from
+// awsConfig's credential provider. If roleArn is non-empty, it is assumed via
+// STS and the resulting, auto-refreshing credentials are used instead of
+// awsConfig's own provider.
+func NewAWSMSKIAMSASLConfig(awsConfig awssdk.Config, roleArn string) SASLConfig {
+	if roleArn != "" {
+		stsClient := sts.NewFromConfig(awsConfig)
+		awsConfig.Credentials = awssdk.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, roleArn))
+	}
+	return SASLConfig{Mechanism: SASLAwsMskIam, AWSConfig: awsConfig}
+}
+
+// mechanism builds the franz-go sasl.Mechanism this SASLConfig describes.
+func (c SASLConfig) mechanism() (sasl.Mechanism, error) {
+	switch c.Mechanism {
+	case SASLNone:
+		return nil, nil
+	case SASLPlain:
+		return plain.Auth{User: c.User, Pass: c.Pass}.AsMechanism(), nil
+	case SASLScramSha256:
+		return scram.Auth{User: c.User, Pass: c.Pass}.AsSha256Mechanism(), nil
+	case SASLScramSha512:
+		return scram.Auth{User: c.User, Pass: c.Pass}.AsSha512Mechanism(), nil
+	case SASLAwsMskIam:
+		return c.awsMechanism(), nil
+	default:
+		return nil, fmt.Errorf("streams: unknown SASLMechanism %d", c.Mechanism)
+	}
+}
+
+// awsMechanism adapts c.AWSConfig's credential provider into a franz-go
+// sasl/aws mechanism. The provider is consulted on every SASL handshake, so
+// credentials backed by an awssdk.CredentialsCache are refreshed before they
+// expire rather than once at startup.
+func (c SASLConfig) awsMechanism() sasl.Mechanism {
+	return awssasl.ManagedStreamingIAM(func(ctx context.Context) (awssasl.Auth, error) {
+		creds, err := c.AWSConfig.Credentials.Retrieve(ctx)
+		if err != nil {
+			return awssasl.Auth{}, err
+		}
+		return awssasl.Auth{
+			AccessKey:    creds.AccessKeyID,
+			SecretKey:    creds.SecretAccessKey,
+			SessionToken: creds.SessionToken,
+		}, nil
+	})
+}
+
+// kgoOpt returns the kgo.Opt that applies this SASLConfig to a kgo.Client, or
+// nil if the mechanism is SASLNone. newPartitionWorker applies it to every
+// producer in the eosProducerPool via eosProducer.setSASL.
+//
+// It is not applied to the source's own consumer kgo.Client, which is built
+// before any partitionWorker exists: only the eosProducerPool's producers
+// authenticate with this SASLConfig.
+func (c SASLConfig) kgoOpt() (kgo.Opt, error) {
+	mech, err := c.mechanism()
+	if err != nil {
+		return nil, err
+	}
+	if mech == nil {
+		return nil, nil
+	}
+	return kgo.SASL(mech), nil
+}